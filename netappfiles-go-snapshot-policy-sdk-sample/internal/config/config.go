@@ -0,0 +1,233 @@
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package config loads the declarative scenario file that drives the
+// sample, replacing the hard-coded globals that used to live in
+// example.go. A scenario file may be YAML or JSON and is resolved from the
+// -config flag or the ANF_SAMPLE_CONFIG environment variable.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// HourlySchedule mirrors netapp.HourlySchedule's configurable fields.
+type HourlySchedule struct {
+	Minute          int32 `yaml:"minute" json:"minute"`
+	SnapshotsToKeep int32 `yaml:"snapshotsToKeep" json:"snapshotsToKeep"`
+}
+
+// DailySchedule mirrors netapp.DailySchedule's configurable fields.
+type DailySchedule struct {
+	Hour            int32 `yaml:"hour" json:"hour"`
+	Minute          int32 `yaml:"minute" json:"minute"`
+	SnapshotsToKeep int32 `yaml:"snapshotsToKeep" json:"snapshotsToKeep"`
+}
+
+// WeeklySchedule mirrors netapp.WeeklySchedule's configurable fields.
+type WeeklySchedule struct {
+	Day             string `yaml:"day" json:"day"`
+	Hour            int32  `yaml:"hour" json:"hour"`
+	Minute          int32  `yaml:"minute" json:"minute"`
+	SnapshotsToKeep int32  `yaml:"snapshotsToKeep" json:"snapshotsToKeep"`
+}
+
+// MonthlySchedule mirrors netapp.MonthlySchedule's configurable fields.
+type MonthlySchedule struct {
+	DaysOfMonth     string `yaml:"daysOfMonth" json:"daysOfMonth"`
+	Hour            int32  `yaml:"hour" json:"hour"`
+	Minute          int32  `yaml:"minute" json:"minute"`
+	SnapshotsToKeep int32  `yaml:"snapshotsToKeep" json:"snapshotsToKeep"`
+}
+
+// SnapshotPolicySpec describes the snapshot policy a scenario should create.
+// Setting Template to the name of a policytemplates preset takes precedence
+// over the Hourly/Daily/Weekly/Monthly blocks below. Enabled is a *bool so a
+// scenario that omits it can fall back to the template's own Enabled value
+// (or to Defaults.SnapshotPolicy.Enabled) instead of silently becoming false.
+type SnapshotPolicySpec struct {
+	Name     string           `yaml:"name" json:"name"`
+	Template string           `yaml:"template,omitempty" json:"template,omitempty"`
+	Enabled  *bool            `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	Hourly   *HourlySchedule  `yaml:"hourly,omitempty" json:"hourly,omitempty"`
+	Daily    *DailySchedule   `yaml:"daily,omitempty" json:"daily,omitempty"`
+	Weekly   *WeeklySchedule  `yaml:"weekly,omitempty" json:"weekly,omitempty"`
+	Monthly  *MonthlySchedule `yaml:"monthly,omitempty" json:"monthly,omitempty"`
+}
+
+// VolumeSpec describes the target volume for a scenario.
+type VolumeSpec struct {
+	Name              string   `yaml:"name" json:"name"`
+	SizeBytes         int64    `yaml:"sizeBytes" json:"sizeBytes"`
+	ServiceLevel      string   `yaml:"serviceLevel" json:"serviceLevel"`
+	ProtocolTypes     []string `yaml:"protocolTypes" json:"protocolTypes"`
+	VnetResourceGroup string   `yaml:"vnetResourceGroup" json:"vnetResourceGroup"`
+	VnetName          string   `yaml:"vnetName" json:"vnetName"`
+	SubnetName        string   `yaml:"subnetName" json:"subnetName"`
+}
+
+// CleanupPolicy controls whether - and how - a scenario tears down what it
+// created. Both fields are *bool so a scenario that omits the cleanup block
+// entirely falls back to Defaults.Cleanup instead of silently disabling it.
+type CleanupPolicy struct {
+	Enabled     *bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	DryRunPrune *bool `yaml:"dryRunPrune,omitempty" json:"dryRunPrune,omitempty"`
+}
+
+// IsEnabled reports whether cleanup should run, defaulting to false when
+// unset.
+func (c CleanupPolicy) IsEnabled() bool {
+	return c.Enabled != nil && *c.Enabled
+}
+
+// IsDryRunPrune reports whether pruning should only log what it would
+// delete, defaulting to false when unset.
+func (c CleanupPolicy) IsDryRunPrune() bool {
+	return c.DryRunPrune != nil && *c.DryRunPrune
+}
+
+// Scenario is one independent run of the sample: its own account, capacity
+// pool, snapshot policy, and volume.
+type Scenario struct {
+	Name           string             `yaml:"name" json:"name"`
+	Location       string             `yaml:"location" json:"location"`
+	ResourceGroup  string             `yaml:"resourceGroup" json:"resourceGroup"`
+	AccountName    string             `yaml:"accountName" json:"accountName"`
+	PoolName       string             `yaml:"poolName" json:"poolName"`
+	PoolSizeBytes  int64              `yaml:"poolSizeBytes" json:"poolSizeBytes"`
+	ServiceLevel   string             `yaml:"serviceLevel" json:"serviceLevel"`
+	Tags           map[string]string  `yaml:"tags" json:"tags"`
+	SnapshotPolicy SnapshotPolicySpec `yaml:"snapshotPolicy" json:"snapshotPolicy"`
+	Volume         VolumeSpec         `yaml:"volume" json:"volume"`
+	Cleanup        CleanupPolicy      `yaml:"cleanup" json:"cleanup"`
+}
+
+// Config is the top-level scenario file: shared defaults plus the list of
+// scenarios to run.
+type Config struct {
+	Defaults  Scenario   `yaml:"defaults" json:"defaults"`
+	Scenarios []Scenario `yaml:"scenarios" json:"scenarios"`
+}
+
+// ResolvePath returns the scenario file path to load: flagValue if set,
+// otherwise the ANF_SAMPLE_CONFIG environment variable, otherwise "".
+func ResolvePath(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	return os.Getenv("ANF_SAMPLE_CONFIG")
+}
+
+// Load reads the scenario file at path, expands ${ENV_VAR} references, and
+// merges each scenario over Config.Defaults. The format (YAML or JSON) is
+// chosen from the file extension.
+func Load(path string) (*Config, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read config file %v: %v", path, err)
+	}
+
+	expanded := os.Expand(string(raw), func(key string) string {
+		return os.Getenv(key)
+	})
+
+	var cfg Config
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal([]byte(expanded), &cfg)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal([]byte(expanded), &cfg)
+	default:
+		return nil, fmt.Errorf("unrecognized config file extension %v, expected .yaml, .yml, or .json", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse config file %v: %v", path, err)
+	}
+
+	for i := range cfg.Scenarios {
+		mergeDefaults(&cfg.Scenarios[i], cfg.Defaults)
+	}
+
+	return &cfg, nil
+}
+
+// mergeDefaults fills any zero-valued field on scenario with the
+// corresponding value from defaults, except the scenario's Name and
+// Volume.Name, which are never taken from defaults.
+func mergeDefaults(scenario *Scenario, defaults Scenario) {
+	if scenario.Location == "" {
+		scenario.Location = defaults.Location
+	}
+	if scenario.ResourceGroup == "" {
+		scenario.ResourceGroup = defaults.ResourceGroup
+	}
+	if scenario.AccountName == "" {
+		scenario.AccountName = defaults.AccountName
+	}
+	if scenario.PoolName == "" {
+		scenario.PoolName = defaults.PoolName
+	}
+	if scenario.PoolSizeBytes == 0 {
+		scenario.PoolSizeBytes = defaults.PoolSizeBytes
+	}
+	if scenario.ServiceLevel == "" {
+		scenario.ServiceLevel = defaults.ServiceLevel
+	}
+	if scenario.Tags == nil {
+		scenario.Tags = defaults.Tags
+	}
+	if scenario.SnapshotPolicy.Name == "" {
+		scenario.SnapshotPolicy.Name = defaults.SnapshotPolicy.Name
+	}
+	if scenario.SnapshotPolicy.Template == "" {
+		scenario.SnapshotPolicy.Template = defaults.SnapshotPolicy.Template
+	}
+	if scenario.SnapshotPolicy.Enabled == nil {
+		scenario.SnapshotPolicy.Enabled = defaults.SnapshotPolicy.Enabled
+	}
+	if scenario.SnapshotPolicy.Hourly == nil {
+		scenario.SnapshotPolicy.Hourly = defaults.SnapshotPolicy.Hourly
+	}
+	if scenario.SnapshotPolicy.Daily == nil {
+		scenario.SnapshotPolicy.Daily = defaults.SnapshotPolicy.Daily
+	}
+	if scenario.SnapshotPolicy.Weekly == nil {
+		scenario.SnapshotPolicy.Weekly = defaults.SnapshotPolicy.Weekly
+	}
+	if scenario.SnapshotPolicy.Monthly == nil {
+		scenario.SnapshotPolicy.Monthly = defaults.SnapshotPolicy.Monthly
+	}
+	if scenario.Volume.SizeBytes == 0 {
+		scenario.Volume.SizeBytes = defaults.Volume.SizeBytes
+	}
+	if scenario.Volume.ServiceLevel == "" {
+		scenario.Volume.ServiceLevel = defaults.Volume.ServiceLevel
+	}
+	if scenario.Volume.ProtocolTypes == nil {
+		scenario.Volume.ProtocolTypes = defaults.Volume.ProtocolTypes
+	}
+	if scenario.Volume.VnetResourceGroup == "" {
+		scenario.Volume.VnetResourceGroup = defaults.Volume.VnetResourceGroup
+	}
+	if scenario.Volume.VnetName == "" {
+		scenario.Volume.VnetName = defaults.Volume.VnetName
+	}
+	if scenario.Volume.SubnetName == "" {
+		scenario.Volume.SubnetName = defaults.Volume.SubnetName
+	}
+	if scenario.Cleanup.Enabled == nil {
+		scenario.Cleanup.Enabled = defaults.Cleanup.Enabled
+	}
+	if scenario.Cleanup.DryRunPrune == nil {
+		scenario.Cleanup.DryRunPrune = defaults.Cleanup.DryRunPrune
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/Azure-Samples/netappfiles-go-snapshot-policy-sdk-sample/netappfiles-go-snapshot-policy-sdk-sample/internal/policytemplates"
+	"github.com/Azure/azure-sdk-for-go/profiles/latest/netapp/mgmt/netapp"
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+// Properties builds the netapp.SnapshotPolicyProperties described by the
+// spec: a named policytemplates preset if Template is set, otherwise the
+// Hourly/Daily/Weekly/Monthly blocks defined inline. The result is validated
+// with policytemplates.Validate before being returned.
+func (s SnapshotPolicySpec) Properties() (*netapp.SnapshotPolicyProperties, error) {
+	var properties *netapp.SnapshotPolicyProperties
+
+	if s.Template != "" {
+		template, ok := policytemplates.Lookup(s.Template)
+		if !ok {
+			return nil, fmt.Errorf("unknown snapshot policy template %q, known templates: %v", s.Template, policytemplates.Names())
+		}
+		properties = template.Build()
+	} else {
+		properties = &netapp.SnapshotPolicyProperties{}
+		if s.Hourly != nil {
+			properties.HourlySchedule = &netapp.HourlySchedule{
+				Minute:          to.Int32Ptr(s.Hourly.Minute),
+				SnapshotsToKeep: to.Int32Ptr(s.Hourly.SnapshotsToKeep),
+			}
+		}
+		if s.Daily != nil {
+			properties.DailySchedule = &netapp.DailySchedule{
+				Hour:            to.Int32Ptr(s.Daily.Hour),
+				Minute:          to.Int32Ptr(s.Daily.Minute),
+				SnapshotsToKeep: to.Int32Ptr(s.Daily.SnapshotsToKeep),
+			}
+		}
+		if s.Weekly != nil {
+			properties.WeeklySchedule = &netapp.WeeklySchedule{
+				Day:             to.StringPtr(s.Weekly.Day),
+				Hour:            to.Int32Ptr(s.Weekly.Hour),
+				Minute:          to.Int32Ptr(s.Weekly.Minute),
+				SnapshotsToKeep: to.Int32Ptr(s.Weekly.SnapshotsToKeep),
+			}
+		}
+		if s.Monthly != nil {
+			properties.MonthlySchedule = &netapp.MonthlySchedule{
+				DaysOfMonth:     to.StringPtr(s.Monthly.DaysOfMonth),
+				Hour:            to.Int32Ptr(s.Monthly.Hour),
+				Minute:          to.Int32Ptr(s.Monthly.Minute),
+				SnapshotsToKeep: to.Int32Ptr(s.Monthly.SnapshotsToKeep),
+			}
+		}
+	}
+
+	// Only override Enabled when the scenario set it explicitly - otherwise
+	// leave whatever the template (or an unset ad-hoc spec) already carries.
+	if s.Enabled != nil {
+		properties.Enabled = to.BoolPtr(*s.Enabled)
+	}
+
+	if err := policytemplates.Validate(properties); err != nil {
+		return nil, err
+	}
+
+	return properties, nil
+}
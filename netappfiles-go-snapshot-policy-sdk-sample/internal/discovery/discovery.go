@@ -0,0 +1,259 @@
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package discovery maintains an in-memory, periodically-refreshed cache of
+// the Azure NetApp Files resources (accounts, capacity pools, volumes) and
+// the subnets they depend on, so a long-running tool doesn't pay a
+// GetResourceByID round trip per resource on every operation.
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Azure-Samples/netappfiles-go-snapshot-policy-sdk-sample/netappfiles-go-snapshot-policy-sdk-sample/internal/sdkutils"
+	"github.com/Azure-Samples/netappfiles-go-snapshot-policy-sdk-sample/netappfiles-go-snapshot-policy-sdk-sample/internal/utils"
+)
+
+// DefaultRefreshInterval is used when a Discoverer is created with a
+// non-positive interval.
+const DefaultRefreshInterval = 10 * time.Minute
+
+// subnetAPIVersion is the ARM API version used when reading a subnet
+// generically via GetResourceByID.
+const subnetAPIVersion = "2019-09-01"
+
+// VolumeRef identifies a volume discovered under a resource group/account/
+// capacity pool, along with the snapshot policy currently attached to it (if
+// any).
+type VolumeRef struct {
+	ResourceGroup    string
+	Account          string
+	Pool             string
+	Volume           string
+	ID               string
+	SnapshotPolicyID string
+}
+
+// PoolRef identifies a capacity pool discovered under a resource group/
+// account.
+type PoolRef struct {
+	ResourceGroup string
+	Account       string
+	Pool          string
+	ID            string
+	Location      string
+	ServiceLevel  string
+}
+
+// SubnetRef is a cached, minimal view of a vnet subnet.
+type SubnetRef struct {
+	ID string
+}
+
+// Discoverer holds the cached resource tree and the background refresh
+// loop that keeps it current.
+type Discoverer struct {
+	resourceGroups []string
+	interval       time.Duration
+
+	mu             sync.RWMutex
+	pools          map[string]PoolRef   // keyed by pool resource ID
+	volumes        map[string]VolumeRef // keyed by volume resource ID
+	subnets        map[string]SubnetRef // keyed by subnet resource ID
+	trackedSubnets map[string]bool      // subnet IDs refreshed on every tick
+	warm           bool
+
+	cancel  context.CancelFunc
+	done    chan struct{}
+	refresh chan struct{}
+}
+
+// NewDiscoverer creates a Discoverer that watches the given resource groups
+// and subnets. A non-positive interval falls back to DefaultRefreshInterval.
+// Subnets looked up later via LookupSubnet are added to the tracked set
+// automatically, so they too stay current on subsequent refreshes.
+func NewDiscoverer(resourceGroups []string, subnetIDs []string, interval time.Duration) *Discoverer {
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+
+	trackedSubnets := make(map[string]bool, len(subnetIDs))
+	for _, subnetID := range subnetIDs {
+		trackedSubnets[subnetID] = true
+	}
+
+	return &Discoverer{
+		resourceGroups: resourceGroups,
+		interval:       interval,
+		pools:          make(map[string]PoolRef),
+		volumes:        make(map[string]VolumeRef),
+		subnets:        make(map[string]SubnetRef),
+		trackedSubnets: trackedSubnets,
+		refresh:        make(chan struct{}, 1),
+	}
+}
+
+// Start performs an initial synchronous refresh and then launches the
+// background refresh loop, ticking every interval until the context is
+// cancelled or Stop is called.
+func (d *Discoverer) Start(ctx context.Context) error {
+	if err := d.ForceRefresh(ctx); err != nil {
+		return fmt.Errorf("cannot perform initial discovery refresh: %v", err)
+	}
+
+	loopCtx, cancel := context.WithCancel(ctx)
+	d.cancel = cancel
+	d.done = make(chan struct{})
+
+	go d.loop(loopCtx)
+
+	return nil
+}
+
+// Stop ends the background refresh loop. It is safe to call multiple times.
+func (d *Discoverer) Stop() {
+	if d.cancel == nil {
+		return
+	}
+	d.cancel()
+	<-d.done
+	d.cancel = nil
+}
+
+// ForceRefresh synchronously rebuilds the cache, bypassing the refresh
+// interval. It is also what the background loop calls on every tick.
+func (d *Discoverer) ForceRefresh(ctx context.Context) error {
+	pools, volumes, err := d.discoverANFTree(ctx)
+	if err != nil {
+		return err
+	}
+
+	d.mu.RLock()
+	trackedSubnets := make([]string, 0, len(d.trackedSubnets))
+	for subnetID := range d.trackedSubnets {
+		trackedSubnets = append(trackedSubnets, subnetID)
+	}
+	d.mu.RUnlock()
+
+	subnets := d.discoverSubnets(ctx, trackedSubnets)
+
+	d.mu.Lock()
+	d.pools = pools
+	d.volumes = volumes
+	d.subnets = subnets
+	d.warm = true
+	d.mu.Unlock()
+
+	return nil
+}
+
+func (d *Discoverer) loop(ctx context.Context) {
+	defer close(d.done)
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.refresh:
+		case <-ticker.C:
+		}
+
+		if err := d.ForceRefresh(ctx); err != nil {
+			utils.ConsoleOutput(fmt.Sprintf("discovery: refresh failed: %v", err))
+		}
+	}
+}
+
+func (d *Discoverer) discoverANFTree(ctx context.Context) (map[string]PoolRef, map[string]VolumeRef, error) {
+	pools := make(map[string]PoolRef)
+	volumes := make(map[string]VolumeRef)
+
+	for _, resourceGroupName := range d.resourceGroups {
+		accounts, err := sdkutils.ListAnfAccounts(ctx, resourceGroupName)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, account := range accounts {
+			if account.Name == nil {
+				continue
+			}
+
+			accountPools, err := sdkutils.ListAnfCapacityPools(ctx, resourceGroupName, *account.Name)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			for _, pool := range accountPools {
+				if pool.Name == nil || pool.ID == nil {
+					continue
+				}
+
+				poolRef := PoolRef{
+					ResourceGroup: resourceGroupName,
+					Account:       *account.Name,
+					Pool:          *pool.Name,
+					ID:            *pool.ID,
+				}
+				if pool.Location != nil {
+					poolRef.Location = *pool.Location
+				}
+				if pool.PoolProperties != nil {
+					poolRef.ServiceLevel = string(pool.ServiceLevel)
+				}
+				pools[poolRef.ID] = poolRef
+
+				poolVolumes, err := sdkutils.ListAnfVolumes(ctx, resourceGroupName, *account.Name, *pool.Name)
+				if err != nil {
+					return nil, nil, err
+				}
+
+				for _, volume := range poolVolumes {
+					if volume.Name == nil || volume.ID == nil {
+						continue
+					}
+
+					volumeRef := VolumeRef{
+						ResourceGroup: resourceGroupName,
+						Account:       *account.Name,
+						Pool:          *pool.Name,
+						Volume:        *volume.Name,
+						ID:            *volume.ID,
+					}
+					if volume.VolumeProperties != nil && volume.DataProtection != nil && volume.DataProtection.Snapshot != nil && volume.DataProtection.Snapshot.SnapshotPolicyID != nil {
+						volumeRef.SnapshotPolicyID = *volume.DataProtection.Snapshot.SnapshotPolicyID
+					}
+					volumes[volumeRef.ID] = volumeRef
+				}
+			}
+		}
+	}
+
+	return pools, volumes, nil
+}
+
+// discoverSubnets re-reads every tracked subnet so the cache reflects their
+// current state instead of whatever was true the first time each one was
+// looked up. A subnet that fails to read (e.g. it was deleted) is dropped
+// from the result rather than failing the whole refresh.
+func (d *Discoverer) discoverSubnets(ctx context.Context, subnetIDs []string) map[string]SubnetRef {
+	subnets := make(map[string]SubnetRef, len(subnetIDs))
+
+	for _, subnetID := range subnetIDs {
+		if _, err := sdkutils.GetResourceByID(ctx, subnetID, subnetAPIVersion); err != nil {
+			utils.ConsoleOutput(fmt.Sprintf("discovery: dropping subnet %v from cache, refresh failed: %v", subnetID, err))
+			continue
+		}
+		subnets[subnetID] = SubnetRef{ID: subnetID}
+	}
+
+	return subnets
+}
@@ -0,0 +1,78 @@
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package discovery
+
+import (
+	"context"
+
+	"github.com/Azure-Samples/netappfiles-go-snapshot-policy-sdk-sample/netappfiles-go-snapshot-policy-sdk-sample/internal/sdkutils"
+)
+
+// LookupSubnet returns the cached subnet for subnetID. On a cache miss (or
+// before the cache has ever been warmed) it falls back to a direct
+// GetResourceByID call and caches the result for next time. Either way,
+// subnetID is added to the tracked set so the background refresh loop keeps
+// it current from here on, instead of caching it forever.
+func (d *Discoverer) LookupSubnet(ctx context.Context, subnetID string) (SubnetRef, error) {
+	d.mu.Lock()
+	subnet, ok := d.subnets[subnetID]
+	d.trackedSubnets[subnetID] = true
+	d.mu.Unlock()
+	if ok {
+		return subnet, nil
+	}
+
+	if _, err := sdkutils.GetResourceByID(ctx, subnetID, subnetAPIVersion); err != nil {
+		return SubnetRef{}, err
+	}
+
+	subnet = SubnetRef{ID: subnetID}
+
+	d.mu.Lock()
+	d.subnets[subnetID] = subnet
+	d.mu.Unlock()
+
+	return subnet, nil
+}
+
+// LookupPoolByServiceLevel returns every cached capacity pool in the given
+// location with the given service level.
+func (d *Discoverer) LookupPoolByServiceLevel(location string, serviceLevel string) []PoolRef {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var matches []PoolRef
+	for _, pool := range d.pools {
+		if pool.Location == location && pool.ServiceLevel == serviceLevel {
+			matches = append(matches, pool)
+		}
+	}
+
+	return matches
+}
+
+// FindPolicyAttachments returns every cached volume whose
+// DataProtection.Snapshot.SnapshotPolicyID matches policyID.
+func (d *Discoverer) FindPolicyAttachments(policyID string) []VolumeRef {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var matches []VolumeRef
+	for _, volume := range d.volumes {
+		if volume.SnapshotPolicyID == policyID {
+			matches = append(matches, volume)
+		}
+	}
+
+	return matches
+}
+
+// IsWarm reports whether the cache has completed at least one refresh.
+func (d *Discoverer) IsWarm() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.warm
+}
@@ -0,0 +1,27 @@
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package iam
+
+import (
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+)
+
+// GetResourceManagementAuthorizer returns an authorizer built from the file
+// pointed to by the AZURE_AUTH_LOCATION environment variable, following the
+// same convention used by the other ANF Go samples.
+func GetResourceManagementAuthorizer() (autorest.Authorizer, error) {
+	authorizer, err := auth.NewAuthorizerFromFile(azureManagementEndpoint())
+	if err != nil {
+		return nil, err
+	}
+
+	return authorizer, nil
+}
+
+func azureManagementEndpoint() string {
+	return "https://management.azure.com/"
+}
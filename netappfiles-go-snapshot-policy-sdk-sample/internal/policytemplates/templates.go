@@ -0,0 +1,145 @@
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package policytemplates models a small set of named Azure NetApp Files
+// snapshot policy presets, plus a validator that catches constraint
+// violations locally before they are sent to the service.
+package policytemplates
+
+import (
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/profiles/latest/netapp/mgmt/netapp"
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+// Template is a named, reusable snapshot policy preset.
+type Template struct {
+	Name        string
+	Description string
+	Build       func() *netapp.SnapshotPolicyProperties
+}
+
+var (
+	// HourlyOnly keeps a rolling window of hourly snapshots and nothing else,
+	// useful for short-lived scratch volumes.
+	HourlyOnly = Template{
+		Name:        "HourlyOnly",
+		Description: "Hourly snapshots only, every hour on the hour, keeping the last 24.",
+		Build: func() *netapp.SnapshotPolicyProperties {
+			return &netapp.SnapshotPolicyProperties{
+				HourlySchedule: &netapp.HourlySchedule{
+					Minute:          to.Int32Ptr(0),
+					SnapshotsToKeep: to.Int32Ptr(24),
+				},
+				Enabled: to.BoolPtr(true),
+			}
+		},
+	}
+
+	// BusinessHours favors daily and weekly coverage, the common default for
+	// volumes that only change during the working day.
+	BusinessHours = Template{
+		Name:        "BusinessHours",
+		Description: "Daily snapshot at 19:00 plus a weekly snapshot on Friday, keeping two weeks.",
+		Build: func() *netapp.SnapshotPolicyProperties {
+			return &netapp.SnapshotPolicyProperties{
+				DailySchedule: &netapp.DailySchedule{
+					Hour:            to.Int32Ptr(19),
+					Minute:          to.Int32Ptr(0),
+					SnapshotsToKeep: to.Int32Ptr(14),
+				},
+				WeeklySchedule: &netapp.WeeklySchedule{
+					Day:             to.StringPtr("Friday"),
+					Hour:            to.Int32Ptr(19),
+					Minute:          to.Int32Ptr(0),
+					SnapshotsToKeep: to.Int32Ptr(8),
+				},
+				Enabled: to.BoolPtr(true),
+			}
+		},
+	}
+
+	// ComplianceRetention maximizes retention across every cadence, for
+	// volumes subject to long audit windows.
+	ComplianceRetention = Template{
+		Name:        "ComplianceRetention",
+		Description: "Daily, weekly, and monthly schedules all kept at the documented maximum.",
+		Build: func() *netapp.SnapshotPolicyProperties {
+			return &netapp.SnapshotPolicyProperties{
+				DailySchedule: &netapp.DailySchedule{
+					Hour:            to.Int32Ptr(0),
+					Minute:          to.Int32Ptr(0),
+					SnapshotsToKeep: to.Int32Ptr(255),
+				},
+				WeeklySchedule: &netapp.WeeklySchedule{
+					Day:             to.StringPtr("Sunday"),
+					Hour:            to.Int32Ptr(0),
+					Minute:          to.Int32Ptr(0),
+					SnapshotsToKeep: to.Int32Ptr(255),
+				},
+				MonthlySchedule: &netapp.MonthlySchedule{
+					DaysOfMonth:     to.StringPtr("1"),
+					Hour:            to.Int32Ptr(0),
+					Minute:          to.Int32Ptr(0),
+					SnapshotsToKeep: to.Int32Ptr(255),
+				},
+				Enabled: to.BoolPtr(true),
+			}
+		},
+	}
+
+	// DRPaired is meant for volumes that are the source of a cross-region
+	// replication: frequent hourly snapshots keep the replica's recovery
+	// point objective tight without the overhead of a long monthly tail.
+	DRPaired = Template{
+		Name:        "DR-Paired",
+		Description: "An hourly snapshot at minute 15 and a daily snapshot, sized for a replicated volume.",
+		Build: func() *netapp.SnapshotPolicyProperties {
+			return &netapp.SnapshotPolicyProperties{
+				HourlySchedule: &netapp.HourlySchedule{
+					Minute:          to.Int32Ptr(15),
+					SnapshotsToKeep: to.Int32Ptr(4),
+				},
+				DailySchedule: &netapp.DailySchedule{
+					Hour:            to.Int32Ptr(0),
+					Minute:          to.Int32Ptr(0),
+					SnapshotsToKeep: to.Int32Ptr(7),
+				},
+				Enabled: to.BoolPtr(true),
+			}
+		},
+	}
+)
+
+// catalog indexes every built-in template by name for lookup by env var or
+// scenario file value.
+var catalog = map[string]Template{
+	HourlyOnly.Name:          HourlyOnly,
+	BusinessHours.Name:       BusinessHours,
+	ComplianceRetention.Name: ComplianceRetention,
+	DRPaired.Name:            DRPaired,
+}
+
+// Lookup returns the named template, or false if no template is registered
+// under that name.
+func Lookup(name string) (Template, bool) {
+	template, ok := catalog[name]
+	return template, ok
+}
+
+// Names returns the registered template names, for use in usage/help text.
+func Names() []string {
+	names := make([]string, 0, len(catalog))
+	for name := range catalog {
+		names = append(names, name)
+	}
+	return names
+}
+
+// String implements fmt.Stringer so a Template can be logged directly.
+func (t Template) String() string {
+	return fmt.Sprintf("%v (%v)", t.Name, t.Description)
+}
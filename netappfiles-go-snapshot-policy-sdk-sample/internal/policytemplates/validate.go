@@ -0,0 +1,152 @@
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package policytemplates
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/profiles/latest/netapp/mgmt/netapp"
+)
+
+// maxSnapshotsToKeep is the documented ANF ceiling for SnapshotsToKeep on
+// every cadence (hourly, daily, weekly, monthly).
+const maxSnapshotsToKeep = 255
+
+// canonicalWeekdays is the set of weekday names the service accepts for a
+// WeeklySchedule.Day.
+var canonicalWeekdays = map[string]bool{
+	"Sunday":    true,
+	"Monday":    true,
+	"Tuesday":   true,
+	"Wednesday": true,
+	"Thursday":  true,
+	"Friday":    true,
+	"Saturday":  true,
+}
+
+// ValidationError aggregates every constraint violation found in a single
+// SnapshotPolicyProperties value so a caller sees all of them at once
+// instead of round-tripping to the API one mistake at a time.
+type ValidationError struct {
+	Violations []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid snapshot policy: %v", strings.Join(e.Violations, "; "))
+}
+
+// Validate enforces the ANF service constraints on a snapshot policy's
+// schedules before it is ever sent over the wire. It returns nil when
+// properties is valid, or a *ValidationError listing every offense.
+func Validate(properties *netapp.SnapshotPolicyProperties) error {
+	var violations []string
+
+	if properties == nil {
+		return &ValidationError{Violations: []string{"snapshot policy properties must not be nil"}}
+	}
+
+	if hourly := properties.HourlySchedule; hourly != nil {
+		violations = append(violations, validateMinute("hourly", hourly.Minute)...)
+		violations = append(violations, validateSnapshotsToKeep("hourly", hourly.SnapshotsToKeep)...)
+	}
+
+	if daily := properties.DailySchedule; daily != nil {
+		violations = append(violations, validateHour("daily", daily.Hour)...)
+		violations = append(violations, validateMinute("daily", daily.Minute)...)
+		violations = append(violations, validateSnapshotsToKeep("daily", daily.SnapshotsToKeep)...)
+	}
+
+	if weekly := properties.WeeklySchedule; weekly != nil {
+		violations = append(violations, validateWeekday(weekly.Day)...)
+		violations = append(violations, validateHour("weekly", weekly.Hour)...)
+		violations = append(violations, validateMinute("weekly", weekly.Minute)...)
+		violations = append(violations, validateSnapshotsToKeep("weekly", weekly.SnapshotsToKeep)...)
+	}
+
+	if monthly := properties.MonthlySchedule; monthly != nil {
+		violations = append(violations, validateDaysOfMonth(monthly.DaysOfMonth)...)
+		violations = append(violations, validateHour("monthly", monthly.Hour)...)
+		violations = append(violations, validateMinute("monthly", monthly.Minute)...)
+		violations = append(violations, validateSnapshotsToKeep("monthly", monthly.SnapshotsToKeep)...)
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Violations: violations}
+}
+
+func validateMinute(cadence string, minute *int32) []string {
+	if minute == nil {
+		return nil
+	}
+	if *minute < 0 || *minute > 59 {
+		return []string{fmt.Sprintf("%v schedule: minute %v out of range [0,59]", cadence, *minute)}
+	}
+	return nil
+}
+
+func validateHour(cadence string, hour *int32) []string {
+	if hour == nil {
+		return nil
+	}
+	if *hour < 0 || *hour > 23 {
+		return []string{fmt.Sprintf("%v schedule: hour %v out of range [0,23]", cadence, *hour)}
+	}
+	return nil
+}
+
+func validateSnapshotsToKeep(cadence string, snapshotsToKeep *int32) []string {
+	if snapshotsToKeep == nil {
+		return nil
+	}
+	if *snapshotsToKeep < 0 || *snapshotsToKeep > maxSnapshotsToKeep {
+		return []string{fmt.Sprintf("%v schedule: snapshotsToKeep %v out of range [0,%v]", cadence, *snapshotsToKeep, maxSnapshotsToKeep)}
+	}
+	return nil
+}
+
+func validateWeekday(day *string) []string {
+	if day == nil {
+		return nil
+	}
+	if !canonicalWeekdays[*day] {
+		return []string{fmt.Sprintf("weekly schedule: day %q is not a recognized weekday", *day)}
+	}
+	return nil
+}
+
+func validateDaysOfMonth(daysOfMonth *string) []string {
+	if daysOfMonth == nil {
+		return nil
+	}
+
+	var violations []string
+	seen := make(map[int]bool)
+
+	for _, part := range strings.Split(*daysOfMonth, ",") {
+		part = strings.TrimSpace(part)
+		day, err := strconv.Atoi(part)
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("monthly schedule: %q is not a valid day of month", part))
+			continue
+		}
+		if day < 1 || day > 31 {
+			violations = append(violations, fmt.Sprintf("monthly schedule: day %v out of range [1,31]", day))
+			continue
+		}
+		if seen[day] {
+			violations = append(violations, fmt.Sprintf("monthly schedule: day %v is duplicated", day))
+			continue
+		}
+		seen[day] = true
+	}
+
+	return violations
+}
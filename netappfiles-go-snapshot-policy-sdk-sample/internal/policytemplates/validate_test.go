@@ -0,0 +1,165 @@
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package policytemplates
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/profiles/latest/netapp/mgmt/netapp"
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties *netapp.SnapshotPolicyProperties
+		wantErr    bool
+	}{
+		{
+			name:       "nil properties",
+			properties: nil,
+			wantErr:    true,
+		},
+		{
+			name:       "empty properties",
+			properties: &netapp.SnapshotPolicyProperties{},
+			wantErr:    false,
+		},
+		{
+			name: "snapshotsToKeep at max boundary is valid",
+			properties: &netapp.SnapshotPolicyProperties{
+				HourlySchedule: &netapp.HourlySchedule{
+					Minute:          to.Int32Ptr(0),
+					SnapshotsToKeep: to.Int32Ptr(255),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "snapshotsToKeep one past max boundary is invalid",
+			properties: &netapp.SnapshotPolicyProperties{
+				HourlySchedule: &netapp.HourlySchedule{
+					Minute:          to.Int32Ptr(0),
+					SnapshotsToKeep: to.Int32Ptr(256),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative snapshotsToKeep is invalid",
+			properties: &netapp.SnapshotPolicyProperties{
+				DailySchedule: &netapp.DailySchedule{
+					Hour:            to.Int32Ptr(0),
+					Minute:          to.Int32Ptr(0),
+					SnapshotsToKeep: to.Int32Ptr(-1),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "hour out of range is invalid",
+			properties: &netapp.SnapshotPolicyProperties{
+				DailySchedule: &netapp.DailySchedule{
+					Hour:            to.Int32Ptr(24),
+					Minute:          to.Int32Ptr(0),
+					SnapshotsToKeep: to.Int32Ptr(5),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "minute out of range is invalid",
+			properties: &netapp.SnapshotPolicyProperties{
+				HourlySchedule: &netapp.HourlySchedule{
+					Minute:          to.Int32Ptr(60),
+					SnapshotsToKeep: to.Int32Ptr(5),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "recognized weekday is valid",
+			properties: &netapp.SnapshotPolicyProperties{
+				WeeklySchedule: &netapp.WeeklySchedule{
+					Day:             to.StringPtr("Friday"),
+					Hour:            to.Int32Ptr(23),
+					Minute:          to.Int32Ptr(0),
+					SnapshotsToKeep: to.Int32Ptr(5),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unrecognized weekday is invalid",
+			properties: &netapp.SnapshotPolicyProperties{
+				WeeklySchedule: &netapp.WeeklySchedule{
+					Day:             to.StringPtr("Someday"),
+					Hour:            to.Int32Ptr(23),
+					Minute:          to.Int32Ptr(0),
+					SnapshotsToKeep: to.Int32Ptr(5),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "monthly days at range boundaries are valid",
+			properties: &netapp.SnapshotPolicyProperties{
+				MonthlySchedule: &netapp.MonthlySchedule{
+					DaysOfMonth:     to.StringPtr("1,15,31"),
+					Hour:            to.Int32Ptr(8),
+					Minute:          to.Int32Ptr(0),
+					SnapshotsToKeep: to.Int32Ptr(5),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "monthly day out of range is invalid",
+			properties: &netapp.SnapshotPolicyProperties{
+				MonthlySchedule: &netapp.MonthlySchedule{
+					DaysOfMonth:     to.StringPtr("0,32"),
+					Hour:            to.Int32Ptr(8),
+					Minute:          to.Int32Ptr(0),
+					SnapshotsToKeep: to.Int32Ptr(5),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate monthly day is invalid",
+			properties: &netapp.SnapshotPolicyProperties{
+				MonthlySchedule: &netapp.MonthlySchedule{
+					DaysOfMonth:     to.StringPtr("1,15,1"),
+					Hour:            to.Int32Ptr(8),
+					Minute:          to.Int32Ptr(0),
+					SnapshotsToKeep: to.Int32Ptr(5),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "malformed monthly day is invalid",
+			properties: &netapp.SnapshotPolicyProperties{
+				MonthlySchedule: &netapp.MonthlySchedule{
+					DaysOfMonth:     to.StringPtr("1,abc"),
+					Hour:            to.Int32Ptr(8),
+					Minute:          to.Int32Ptr(0),
+					SnapshotsToKeep: to.Int32Ptr(5),
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.properties)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package sdkutils
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/profiles/latest/netapp/mgmt/netapp"
+)
+
+// AttachSnapshotPolicyToVolume patches an existing volume's
+// DataProtection.Snapshot.SnapshotPolicyID to point at snapshotPolicyID,
+// leaving every other volume property untouched. It is a no-op if the
+// volume is already attached to that policy, and waits until the volume
+// returns to a "Succeeded" provisioning state before returning.
+func AttachSnapshotPolicyToVolume(ctx context.Context, resourceGroupName string, accountName string, poolName string, volumeName string, snapshotPolicyID string) error {
+	return patchVolumeSnapshotPolicy(ctx, resourceGroupName, accountName, poolName, volumeName, &snapshotPolicyID)
+}
+
+// DetachSnapshotPolicyFromVolume clears a volume's
+// DataProtection.Snapshot.SnapshotPolicyID, leaving every other volume
+// property untouched. It is a no-op if the volume has no snapshot policy
+// attached, and waits until the volume returns to a "Succeeded"
+// provisioning state before returning.
+func DetachSnapshotPolicyFromVolume(ctx context.Context, resourceGroupName string, accountName string, poolName string, volumeName string) error {
+	return patchVolumeSnapshotPolicy(ctx, resourceGroupName, accountName, poolName, volumeName, nil)
+}
+
+func patchVolumeSnapshotPolicy(ctx context.Context, resourceGroupName string, accountName string, poolName string, volumeName string, snapshotPolicyID *string) error {
+	client, err := getVolumesClient()
+	if err != nil {
+		return err
+	}
+
+	volume, err := client.Get(ctx, resourceGroupName, accountName, poolName, volumeName)
+	if err != nil {
+		return fmt.Errorf("cannot get volume %v: %v", volumeName, err)
+	}
+
+	if currentPolicyMatches(volume, snapshotPolicyID) {
+		return nil
+	}
+
+	patch := netapp.VolumePatch{
+		Location: volume.Location,
+		VolumePatchProperties: &netapp.VolumePatchProperties{
+			DataProtection: &netapp.VolumePatchPropertiesDataProtection{
+				Snapshot: &netapp.VolumeSnapshotProperties{
+					SnapshotPolicyID: snapshotPolicyID,
+				},
+			},
+		},
+	}
+
+	future, err := client.Update(ctx, patch, resourceGroupName, accountName, poolName, volumeName)
+	if err != nil {
+		return fmt.Errorf("cannot patch volume %v: %v", volumeName, err)
+	}
+
+	if err := future.WaitForCompletionRef(ctx, client.Client); err != nil {
+		return fmt.Errorf("cannot get volume update future response: %v", err)
+	}
+
+	updated, err := future.Result(client)
+	if err != nil {
+		return err
+	}
+
+	return WaitForANFResource(ctx, *updated.ID, 60, 50, false)
+}
+
+func currentPolicyMatches(volume netapp.Volume, snapshotPolicyID *string) bool {
+	if volume.DataProtection == nil || volume.DataProtection.Snapshot == nil {
+		return snapshotPolicyID == nil
+	}
+
+	current := volume.DataProtection.Snapshot.SnapshotPolicyID
+	if current == nil || snapshotPolicyID == nil {
+		return current == snapshotPolicyID
+	}
+
+	return *current == *snapshotPolicyID
+}
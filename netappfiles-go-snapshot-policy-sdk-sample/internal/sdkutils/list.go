@@ -0,0 +1,82 @@
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package sdkutils
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/profiles/latest/netapp/mgmt/netapp"
+)
+
+// ListAnfAccounts returns every Azure NetApp Files account in a resource
+// group, paging through the result set as needed.
+func ListAnfAccounts(ctx context.Context, resourceGroupName string) ([]netapp.Account, error) {
+	client, err := getAccountsClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts []netapp.Account
+	page, err := client.List(ctx, resourceGroupName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list accounts in %v: %v", resourceGroupName, err)
+	}
+	for ; page.NotDone(); err = page.NextWithContext(ctx) {
+		if err != nil {
+			return nil, fmt.Errorf("cannot list accounts in %v: %v", resourceGroupName, err)
+		}
+		accounts = append(accounts, page.Values()...)
+	}
+
+	return accounts, nil
+}
+
+// ListAnfCapacityPools returns every capacity pool under an Azure NetApp
+// Files account, paging through the result set as needed.
+func ListAnfCapacityPools(ctx context.Context, resourceGroupName string, accountName string) ([]netapp.CapacityPool, error) {
+	client, err := getPoolsClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var pools []netapp.CapacityPool
+	page, err := client.List(ctx, resourceGroupName, accountName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list capacity pools in %v/%v: %v", resourceGroupName, accountName, err)
+	}
+	for ; page.NotDone(); err = page.NextWithContext(ctx) {
+		if err != nil {
+			return nil, fmt.Errorf("cannot list capacity pools in %v/%v: %v", resourceGroupName, accountName, err)
+		}
+		pools = append(pools, page.Values()...)
+	}
+
+	return pools, nil
+}
+
+// ListAnfVolumes returns every volume under an Azure NetApp Files capacity
+// pool, paging through the result set as needed.
+func ListAnfVolumes(ctx context.Context, resourceGroupName string, accountName string, poolName string) ([]netapp.Volume, error) {
+	client, err := getVolumesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var volumes []netapp.Volume
+	page, err := client.List(ctx, resourceGroupName, accountName, poolName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list volumes in %v/%v/%v: %v", resourceGroupName, accountName, poolName, err)
+	}
+	for ; page.NotDone(); err = page.NextWithContext(ctx) {
+		if err != nil {
+			return nil, fmt.Errorf("cannot list volumes in %v/%v/%v: %v", resourceGroupName, accountName, poolName, err)
+		}
+		volumes = append(volumes, page.Values()...)
+	}
+
+	return volumes, nil
+}
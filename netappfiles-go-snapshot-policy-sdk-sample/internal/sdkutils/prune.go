@@ -0,0 +1,171 @@
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package sdkutils
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Azure-Samples/netappfiles-go-snapshot-policy-sdk-sample/netappfiles-go-snapshot-policy-sdk-sample/internal/utils"
+	"github.com/Azure/azure-sdk-for-go/profiles/latest/netapp/mgmt/netapp"
+)
+
+// policyBucketPrefixes lists the snapshot name prefixes produced by an ANF
+// snapshot policy; keepFor maps each one to the SnapshotRetention field that
+// governs how many of them survive a prune.
+var policyBucketPrefixes = []string{"hourly-", "daily-", "weekly-", "monthly-"}
+
+// SnapshotRetention overrides how many snapshots to keep per policy bucket.
+// A zero value for a given field means "use the count from the live
+// SnapshotPolicy instead". Setting DryRun logs what would be deleted
+// without actually deleting anything.
+type SnapshotRetention struct {
+	Hourly  int32
+	Daily   int32
+	Weekly  int32
+	Monthly int32
+	DryRun  bool
+}
+
+func (r SnapshotRetention) keepFor(bucketPrefix string, policy netapp.SnapshotPolicy) int32 {
+	override := func(value int32) (int32, bool) {
+		if value > 0 {
+			return value, true
+		}
+		return 0, false
+	}
+
+	switch bucketPrefix {
+	case "hourly-":
+		if v, ok := override(r.Hourly); ok {
+			return v
+		}
+		if policy.HourlySchedule != nil && policy.HourlySchedule.SnapshotsToKeep != nil {
+			return *policy.HourlySchedule.SnapshotsToKeep
+		}
+	case "daily-":
+		if v, ok := override(r.Daily); ok {
+			return v
+		}
+		if policy.DailySchedule != nil && policy.DailySchedule.SnapshotsToKeep != nil {
+			return *policy.DailySchedule.SnapshotsToKeep
+		}
+	case "weekly-":
+		if v, ok := override(r.Weekly); ok {
+			return v
+		}
+		if policy.WeeklySchedule != nil && policy.WeeklySchedule.SnapshotsToKeep != nil {
+			return *policy.WeeklySchedule.SnapshotsToKeep
+		}
+	case "monthly-":
+		if v, ok := override(r.Monthly); ok {
+			return v
+		}
+		if policy.MonthlySchedule != nil && policy.MonthlySchedule.SnapshotsToKeep != nil {
+			return *policy.MonthlySchedule.SnapshotsToKeep
+		}
+	}
+
+	return 0
+}
+
+// PruneSnapshotsByPolicy deletes policy-generated snapshots on a volume that
+// exceed the SnapshotsToKeep figure for their bucket (hourly/daily/weekly/
+// monthly), derived from the policy identified by policyID unless overridden
+// via retain. Snapshots not matching one of the policy-generated name
+// prefixes are left alone. With retain.DryRun set, nothing is deleted and
+// each candidate is only logged.
+func PruneSnapshotsByPolicy(ctx context.Context, resourceGroupName string, accountName string, poolName string, volumeName string, policyID string, retain SnapshotRetention) error {
+	snapshotsClient, err := getSnapshotsClient()
+	if err != nil {
+		return err
+	}
+
+	policy, err := policyByID(ctx, resourceGroupName, accountName, policyID)
+	if err != nil {
+		return fmt.Errorf("cannot resolve snapshot policy %v: %v", policyID, err)
+	}
+
+	list, err := snapshotsClient.List(ctx, resourceGroupName, accountName, poolName, volumeName)
+	if err != nil {
+		return fmt.Errorf("cannot list snapshots on volume %v: %v", volumeName, err)
+	}
+	if list.Value == nil {
+		return nil
+	}
+
+	buckets := make(map[string][]netapp.Snapshot)
+	for _, snapshot := range *list.Value {
+		if snapshot.Name == nil {
+			continue
+		}
+		for _, prefix := range policyBucketPrefixes {
+			if strings.HasPrefix(*snapshot.Name, prefix) {
+				buckets[prefix] = append(buckets[prefix], snapshot)
+				break
+			}
+		}
+	}
+
+	for _, prefix := range policyBucketPrefixes {
+		snapshots := buckets[prefix]
+		if len(snapshots) == 0 {
+			continue
+		}
+
+		sort.Slice(snapshots, func(i, j int) bool {
+			return snapshotCreated(snapshots[i]).After(snapshotCreated(snapshots[j]))
+		})
+
+		keep := retain.keepFor(prefix, policy)
+		if int32(len(snapshots)) <= keep {
+			continue
+		}
+
+		for _, snapshot := range snapshots[keep:] {
+			if retain.DryRun {
+				utils.ConsoleOutput(fmt.Sprintf("\t[dry-run] would delete snapshot %v", *snapshot.Name))
+				continue
+			}
+
+			utils.ConsoleOutput(fmt.Sprintf("\tDeleting snapshot %v", *snapshot.Name))
+			future, err := snapshotsClient.Delete(ctx, resourceGroupName, accountName, poolName, volumeName, *snapshot.Name)
+			if err != nil {
+				return fmt.Errorf("cannot delete snapshot %v: %v", *snapshot.Name, err)
+			}
+			if err := future.WaitForCompletionRef(ctx, snapshotsClient.Client); err != nil {
+				return fmt.Errorf("cannot get snapshot delete future response for %v: %v", *snapshot.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func policyByID(ctx context.Context, resourceGroupName string, accountName string, policyID string) (netapp.SnapshotPolicy, error) {
+	policies, err := ListAnfSnapshotPolicies(ctx, resourceGroupName, accountName)
+	if err != nil {
+		return netapp.SnapshotPolicy{}, err
+	}
+
+	for _, policy := range policies {
+		if policy.ID != nil && *policy.ID == policyID {
+			return policy, nil
+		}
+	}
+
+	return netapp.SnapshotPolicy{}, fmt.Errorf("snapshot policy %v not found in account %v", policyID, accountName)
+}
+
+func snapshotCreated(snapshot netapp.Snapshot) time.Time {
+	if snapshot.Created == nil {
+		return time.Time{}
+	}
+	return snapshot.Created.Time
+}
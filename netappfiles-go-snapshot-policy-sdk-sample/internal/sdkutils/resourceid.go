@@ -0,0 +1,47 @@
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package sdkutils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseVolumeResourceID splits an ANF volume resource ID of the form
+// "/subscriptions/{sub}/resourceGroups/{rg}/providers/Microsoft.NetApp/netAppAccounts/{account}/capacityPools/{pool}/volumes/{volume}"
+// into its resource group, account, capacity pool, and volume names.
+func ParseVolumeResourceID(volumeResourceID string) (resourceGroupName string, accountName string, poolName string, volumeName string, err error) {
+	parts := strings.Split(strings.Trim(volumeResourceID, "/"), "/")
+
+	indexByKey := make(map[string]int, len(parts))
+	for i, part := range parts {
+		indexByKey[strings.ToLower(part)] = i
+	}
+
+	valueAfter := func(key string) (string, bool) {
+		i, ok := indexByKey[key]
+		if !ok || i+1 >= len(parts) {
+			return "", false
+		}
+		return parts[i+1], true
+	}
+
+	var ok bool
+	if resourceGroupName, ok = valueAfter("resourcegroups"); !ok {
+		return "", "", "", "", fmt.Errorf("cannot parse resource group name from volume resource id %v", volumeResourceID)
+	}
+	if accountName, ok = valueAfter("netappaccounts"); !ok {
+		return "", "", "", "", fmt.Errorf("cannot parse account name from volume resource id %v", volumeResourceID)
+	}
+	if poolName, ok = valueAfter("capacitypools"); !ok {
+		return "", "", "", "", fmt.Errorf("cannot parse capacity pool name from volume resource id %v", volumeResourceID)
+	}
+	if volumeName, ok = valueAfter("volumes"); !ok {
+		return "", "", "", "", fmt.Errorf("cannot parse volume name from volume resource id %v", volumeResourceID)
+	}
+
+	return resourceGroupName, accountName, poolName, volumeName, nil
+}
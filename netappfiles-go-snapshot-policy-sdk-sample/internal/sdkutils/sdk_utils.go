@@ -0,0 +1,318 @@
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package sdkutils wraps the Azure NetApp Files management SDK calls used by
+// the sample, centralizing client creation, polling, and generic resource
+// lookups so example.go can stay focused on scenario orchestration.
+package sdkutils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Azure-Samples/netappfiles-go-snapshot-policy-sdk-sample/netappfiles-go-snapshot-policy-sdk-sample/internal/iam"
+	"github.com/Azure/azure-sdk-for-go/profiles/latest/netapp/mgmt/netapp"
+	"github.com/Azure/azure-sdk-for-go/profiles/latest/resources/mgmt/resources"
+	"github.com/Azure/go-autorest/autorest"
+)
+
+func getSubscriptionID() string {
+	return os.Getenv("AZURE_SUBSCRIPTION_ID")
+}
+
+func getAuthorizer() (autorest.Authorizer, error) {
+	return iam.GetResourceManagementAuthorizer()
+}
+
+func getAccountsClient() (netapp.AccountsClient, error) {
+	client := netapp.NewAccountsClient(getSubscriptionID())
+	authorizer, err := getAuthorizer()
+	if err != nil {
+		return client, err
+	}
+	client.Authorizer = authorizer
+	return client, nil
+}
+
+func getPoolsClient() (netapp.PoolsClient, error) {
+	client := netapp.NewPoolsClient(getSubscriptionID())
+	authorizer, err := getAuthorizer()
+	if err != nil {
+		return client, err
+	}
+	client.Authorizer = authorizer
+	return client, nil
+}
+
+func getVolumesClient() (netapp.VolumesClient, error) {
+	client := netapp.NewVolumesClient(getSubscriptionID())
+	authorizer, err := getAuthorizer()
+	if err != nil {
+		return client, err
+	}
+	client.Authorizer = authorizer
+	return client, nil
+}
+
+func getSnapshotPoliciesClient() (netapp.SnapshotPoliciesClient, error) {
+	client := netapp.NewSnapshotPoliciesClient(getSubscriptionID())
+	authorizer, err := getAuthorizer()
+	if err != nil {
+		return client, err
+	}
+	client.Authorizer = authorizer
+	return client, nil
+}
+
+func getSnapshotsClient() (netapp.SnapshotsClient, error) {
+	client := netapp.NewSnapshotsClient(getSubscriptionID())
+	authorizer, err := getAuthorizer()
+	if err != nil {
+		return client, err
+	}
+	client.Authorizer = authorizer
+	return client, nil
+}
+
+func getResourcesClient() (resources.Client, error) {
+	client := resources.NewClient(getSubscriptionID())
+	authorizer, err := getAuthorizer()
+	if err != nil {
+		return client, err
+	}
+	client.Authorizer = authorizer
+	return client, nil
+}
+
+// GetResourceByID performs a generic ARM GET against the provided resource
+// ID, used to check for the existence of resources (e.g. a vnet/subnet) that
+// this sample does not otherwise manage.
+func GetResourceByID(ctx context.Context, resourceID string, apiVersion string) (resources.GenericResource, error) {
+	client, err := getResourcesClient()
+	if err != nil {
+		return resources.GenericResource{}, err
+	}
+
+	return client.GetByID(ctx, resourceID, apiVersion)
+}
+
+// CreateAnfAccount creates an Azure NetApp Files account.
+func CreateAnfAccount(ctx context.Context, location string, resourceGroupName string, accountName string, activeDirectories []netapp.ActiveDirectory, tags map[string]*string) (netapp.Account, error) {
+	client, err := getAccountsClient()
+	if err != nil {
+		return netapp.Account{}, err
+	}
+
+	accountBody := netapp.Account{
+		Location: &location,
+		AccountProperties: &netapp.AccountProperties{
+			ActiveDirectories: &activeDirectories,
+		},
+		Tags: tags,
+	}
+
+	future, err := client.CreateOrUpdate(ctx, accountBody, resourceGroupName, accountName)
+	if err != nil {
+		return netapp.Account{}, fmt.Errorf("cannot create account: %v", err)
+	}
+
+	err = future.WaitForCompletionRef(ctx, client.Client)
+	if err != nil {
+		return netapp.Account{}, fmt.Errorf("cannot get account create or update future response: %v", err)
+	}
+
+	return future.Result(client)
+}
+
+// CreateAnfCapacityPool creates an Azure NetApp Files capacity pool.
+func CreateAnfCapacityPool(ctx context.Context, location string, resourceGroupName string, accountName string, poolName string, serviceLevel string, sizeBytes int64, tags map[string]*string) (netapp.CapacityPool, error) {
+	client, err := getPoolsClient()
+	if err != nil {
+		return netapp.CapacityPool{}, err
+	}
+
+	poolBody := netapp.CapacityPool{
+		Location: &location,
+		PoolProperties: &netapp.PoolProperties{
+			ServiceLevel: netapp.ServiceLevel(serviceLevel),
+			Size:         &sizeBytes,
+		},
+		Tags: tags,
+	}
+
+	future, err := client.CreateOrUpdate(ctx, poolBody, resourceGroupName, accountName, poolName)
+	if err != nil {
+		return netapp.CapacityPool{}, fmt.Errorf("cannot create capacity pool: %v", err)
+	}
+
+	err = future.WaitForCompletionRef(ctx, client.Client)
+	if err != nil {
+		return netapp.CapacityPool{}, fmt.Errorf("cannot get capacity pool create or update future response: %v", err)
+	}
+
+	return future.Result(client)
+}
+
+// CreateAnfVolume creates an Azure NetApp Files volume.
+func CreateAnfVolume(ctx context.Context, location string, resourceGroupName string, accountName string, poolName string, volumeName string, serviceLevel string, subnetID string, volumePath string, protocolTypes []string, sizeBytes int64, unixReadOnly bool, unixReadWrite bool, tags map[string]*string, dataProtectionObject netapp.VolumePropertiesDataProtection) (netapp.Volume, error) {
+	client, err := getVolumesClient()
+	if err != nil {
+		return netapp.Volume{}, err
+	}
+
+	if volumePath == "" {
+		volumePath = volumeName
+	}
+
+	volumeBody := netapp.Volume{
+		Location: &location,
+		VolumeProperties: &netapp.VolumeProperties{
+			ServiceLevel:   netapp.ServiceLevel(serviceLevel),
+			CreationToken:  &volumePath,
+			SubnetID:       &subnetID,
+			ProtocolTypes:  &protocolTypes,
+			UsageThreshold: &sizeBytes,
+			DataProtection: &dataProtectionObject,
+		},
+		Tags: tags,
+	}
+
+	future, err := client.CreateOrUpdate(ctx, volumeBody, resourceGroupName, accountName, poolName, volumeName)
+	if err != nil {
+		return netapp.Volume{}, fmt.Errorf("cannot create volume: %v", err)
+	}
+
+	err = future.WaitForCompletionRef(ctx, client.Client)
+	if err != nil {
+		return netapp.Volume{}, fmt.Errorf("cannot get volume create or update future response: %v", err)
+	}
+
+	return future.Result(client)
+}
+
+// CreateAnfSnapshotPolicy creates an Azure NetApp Files snapshot policy.
+func CreateAnfSnapshotPolicy(ctx context.Context, resourceGroupName string, accountName string, snapshotPolicyName string, snapshotPolicyBody netapp.SnapshotPolicy) (netapp.SnapshotPolicy, error) {
+	client, err := getSnapshotPoliciesClient()
+	if err != nil {
+		return netapp.SnapshotPolicy{}, err
+	}
+
+	return client.Create(ctx, snapshotPolicyBody, resourceGroupName, accountName, snapshotPolicyName)
+}
+
+// DeleteAnfVolumeReplication removes a volume's data protection/replication
+// relationship, if any.
+func DeleteAnfVolumeReplication(ctx context.Context, resourceGroupName string, accountName string, poolName string, volumeName string) error {
+	client, err := getVolumesClient()
+	if err != nil {
+		return err
+	}
+
+	future, err := client.DeleteReplication(ctx, resourceGroupName, accountName, poolName, volumeName)
+	if err != nil {
+		return fmt.Errorf("cannot delete volume replication: %v", err)
+	}
+
+	return future.WaitForCompletionRef(ctx, client.Client)
+}
+
+// DeleteAnfVolume deletes an Azure NetApp Files volume.
+func DeleteAnfVolume(ctx context.Context, resourceGroupName string, accountName string, poolName string, volumeName string) error {
+	client, err := getVolumesClient()
+	if err != nil {
+		return err
+	}
+
+	future, err := client.Delete(ctx, resourceGroupName, accountName, poolName, volumeName)
+	if err != nil {
+		return fmt.Errorf("cannot delete volume: %v", err)
+	}
+
+	return future.WaitForCompletionRef(ctx, client.Client)
+}
+
+// DeleteAnfCapacityPool deletes an Azure NetApp Files capacity pool.
+func DeleteAnfCapacityPool(ctx context.Context, resourceGroupName string, accountName string, poolName string) error {
+	client, err := getPoolsClient()
+	if err != nil {
+		return err
+	}
+
+	future, err := client.Delete(ctx, resourceGroupName, accountName, poolName)
+	if err != nil {
+		return fmt.Errorf("cannot delete capacity pool: %v", err)
+	}
+
+	return future.WaitForCompletionRef(ctx, client.Client)
+}
+
+// DeleteAnfAccount deletes an Azure NetApp Files account.
+func DeleteAnfAccount(ctx context.Context, resourceGroupName string, accountName string) error {
+	client, err := getAccountsClient()
+	if err != nil {
+		return err
+	}
+
+	future, err := client.Delete(ctx, resourceGroupName, accountName)
+	if err != nil {
+		return fmt.Errorf("cannot delete account: %v", err)
+	}
+
+	return future.WaitForCompletionRef(ctx, client.Client)
+}
+
+// anfAPIVersion is the ARM API version used when polling ANF resources
+// generically via GetResourceByID.
+const anfAPIVersion string = "2020-09-01"
+
+// WaitForANFResource polls the provided resource ID until its provisioning
+// state reaches "Succeeded", up to the provided number of retries and
+// interval (in seconds). It returns an error as soon as the provisioning
+// state is "Failed". When checkForReplication is true, the replication
+// status sub-property is polled instead of the top level provisioning state.
+func WaitForANFResource(ctx context.Context, resourceID string, intervalInSeconds int, retries int, checkForReplication bool) error {
+	for i := 0; i < retries; i++ {
+		resource, err := GetResourceByID(ctx, resourceID, anfAPIVersion)
+		if err == nil {
+			properties, _ := resource.Properties.(map[string]interface{})
+			if checkForReplication {
+				if mirrorState, ok := properties["mirrorState"]; ok && mirrorState == "Mirrored" {
+					return nil
+				}
+			} else if state, ok := properties["provisioningState"]; ok && state == "Succeeded" {
+				return nil
+			} else if ok && state == "Failed" {
+				return fmt.Errorf("resource %v reached provisioning state Failed", resourceID)
+			}
+		}
+
+		time.Sleep(time.Duration(intervalInSeconds) * time.Second)
+	}
+
+	return fmt.Errorf("timed out waiting for resource %v to be ready", resourceID)
+}
+
+// WaitForNoANFResource polls the provided resource ID until it (or, when
+// checkForReplication is true, its replication relationship) no longer
+// exists, up to the provided number of retries and interval (in seconds).
+func WaitForNoANFResource(ctx context.Context, resourceID string, intervalInSeconds int, retries int, checkForReplication bool) error {
+	for i := 0; i < retries; i++ {
+		resource, err := GetResourceByID(ctx, resourceID, anfAPIVersion)
+		if checkForReplication {
+			properties, _ := resource.Properties.(map[string]interface{})
+			if _, ok := properties["mirrorState"]; err != nil || !ok {
+				return nil
+			}
+		} else if err != nil {
+			return nil
+		}
+
+		time.Sleep(time.Duration(intervalInSeconds) * time.Second)
+	}
+
+	return fmt.Errorf("timed out waiting for resource %v to be removed", resourceID)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package sdkutils
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/profiles/latest/netapp/mgmt/netapp"
+)
+
+// UpdateAnfSnapshotPolicy patches an existing Azure NetApp Files snapshot
+// policy, mutating only the fields set on patch (e.g. disabling the hourly
+// schedule or changing SnapshotsToKeep on the daily schedule) without
+// requiring the caller to resend the full set of schedules.
+func UpdateAnfSnapshotPolicy(ctx context.Context, resourceGroupName string, accountName string, policyName string, patch netapp.SnapshotPolicyPatch) (netapp.SnapshotPolicy, error) {
+	client, err := getSnapshotPoliciesClient()
+	if err != nil {
+		return netapp.SnapshotPolicy{}, err
+	}
+
+	future, err := client.Update(ctx, patch, resourceGroupName, accountName, policyName)
+	if err != nil {
+		return netapp.SnapshotPolicy{}, fmt.Errorf("cannot update snapshot policy: %v", err)
+	}
+
+	err = future.WaitForCompletionRef(ctx, client.Client)
+	if err != nil {
+		return netapp.SnapshotPolicy{}, fmt.Errorf("cannot get snapshot policy update future response: %v", err)
+	}
+
+	return future.Result(client)
+}
+
+// GetAnfSnapshotPolicy retrieves a single Azure NetApp Files snapshot policy.
+func GetAnfSnapshotPolicy(ctx context.Context, resourceGroupName string, accountName string, policyName string) (netapp.SnapshotPolicy, error) {
+	client, err := getSnapshotPoliciesClient()
+	if err != nil {
+		return netapp.SnapshotPolicy{}, err
+	}
+
+	return client.Get(ctx, resourceGroupName, accountName, policyName)
+}
+
+// ListAnfSnapshotPolicies returns the snapshot policies defined under the
+// given Azure NetApp Files account from a single List call; it does not page
+// through further results.
+func ListAnfSnapshotPolicies(ctx context.Context, resourceGroupName string, accountName string) ([]netapp.SnapshotPolicy, error) {
+	client, err := getSnapshotPoliciesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := client.List(ctx, resourceGroupName, accountName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list snapshot policies: %v", err)
+	}
+
+	if list.Value == nil {
+		return []netapp.SnapshotPolicy{}, nil
+	}
+
+	return *list.Value, nil
+}
@@ -0,0 +1,57 @@
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strings"
+)
+
+// AzureAuthInfo holds the non-sensitive bits of the SDK auth file that the
+// samples need in order to build resource IDs (e.g. the subscription ID).
+type AzureAuthInfo struct {
+	ClientID       *string `json:"clientId"`
+	SubscriptionID *string `json:"subscriptionId"`
+	TenantID       *string `json:"tenantId"`
+}
+
+// ReadAzureBasicInfoJSON reads the SDK auth file referenced by the
+// AZURE_AUTH_LOCATION environment variable and returns the subset of fields
+// that are safe to keep in memory and log.
+func ReadAzureBasicInfoJSON(path string) (*AzureAuthInfo, error) {
+	if path == "" {
+		return nil, fmt.Errorf("AZURE_AUTH_LOCATION environment variable was not set")
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var info AzureAuthInfo
+	if err := json.Unmarshal(content, &info); err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+// PrintHeader prints a banner around the provided message, used at the top
+// of each sample run.
+func PrintHeader(message string) {
+	dashes := strings.Repeat("-", len(message))
+	log.Println(dashes)
+	log.Println(message)
+	log.Println(dashes)
+}
+
+// ConsoleOutput writes a single line of sample progress output.
+func ConsoleOutput(message string) {
+	log.Println(message)
+}
@@ -0,0 +1,339 @@
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Azure-Samples/netappfiles-go-snapshot-policy-sdk-sample/netappfiles-go-snapshot-policy-sdk-sample/internal/config"
+	"github.com/Azure-Samples/netappfiles-go-snapshot-policy-sdk-sample/netappfiles-go-snapshot-policy-sdk-sample/internal/discovery"
+	"github.com/Azure-Samples/netappfiles-go-snapshot-policy-sdk-sample/netappfiles-go-snapshot-policy-sdk-sample/internal/sdkutils"
+	"github.com/Azure-Samples/netappfiles-go-snapshot-policy-sdk-sample/netappfiles-go-snapshot-policy-sdk-sample/internal/utils"
+	"github.com/Azure/azure-sdk-for-go/profiles/latest/netapp/mgmt/netapp"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/yelinaung/go-haikunator"
+)
+
+// scenarioResources tracks the resource IDs a scenario has actually created,
+// so cleanup only ever tears down what this run is responsible for.
+type scenarioResources struct {
+	accountID        string
+	capacityPoolID   string
+	volumeID         string
+	snapshotPolicyID string
+}
+
+// runScenario executes one scenario end to end. With attachToVolumeID unset
+// it creates an account, capacity pool, and snapshot policy from scratch,
+// patches that policy and polls until it reaches a terminal provisioning
+// state, and only then creates the volume with the patched policy attached.
+// With attachToVolumeID set it attaches to a pre-existing production volume
+// instead: no account or capacity pool is created, and the snapshot policy
+// is upserted (by name) into that volume's existing account rather than
+// minted fresh, so repeated --attach-to runs don't leak scaffolding into
+// the subscription. Cleanup runs in reverse order on the way out when
+// scenario.Cleanup.IsEnabled() is true, but only for the resources this
+// invocation actually created, and never in attach mode.
+func runScenario(cntx context.Context, discoverer *discovery.Discoverer, scenario config.Scenario, subscriptionID string, attachToVolumeID string) error {
+	var resources scenarioResources
+
+	defer func() {
+		if scenario.Cleanup.IsEnabled() && attachToVolumeID == "" {
+			cleanupScenario(cntx, scenario, resources)
+		}
+	}()
+
+	tags := toStringPtrTags(scenario.Tags)
+
+	policyResourceGroup := scenario.ResourceGroup
+	policyAccountName := scenario.AccountName
+	var attachPool, attachVolume string
+
+	if attachToVolumeID != "" {
+		rg, accountName, pool, volume, err := sdkutils.ParseVolumeResourceID(attachToVolumeID)
+		if err != nil {
+			return fmt.Errorf("cannot parse --attach-to volume id: %v", err)
+		}
+		policyResourceGroup = rg
+		policyAccountName = accountName
+		attachPool = pool
+		attachVolume = volume
+	} else {
+		subnetID := fmt.Sprintf("/subscriptions/%v/resourceGroups/%v/providers/Microsoft.Network/virtualNetworks/%v/subnets/%v",
+			subscriptionID,
+			scenario.Volume.VnetResourceGroup,
+			scenario.Volume.VnetName,
+			scenario.Volume.SubnetName,
+		)
+
+		utils.ConsoleOutput(fmt.Sprintf("Checking if vnet/subnet %v exists.", subnetID))
+		if _, err := discoverer.LookupSubnet(cntx, subnetID); err != nil {
+			return fmt.Errorf("subnet %v not found: %v", subnetID, err)
+		}
+
+		utils.ConsoleOutput(fmt.Sprintf("Creating Azure NetApp Files account %v...", scenario.AccountName))
+		account, err := sdkutils.CreateAnfAccount(cntx, scenario.Location, scenario.ResourceGroup, scenario.AccountName, nil, tags)
+		if err != nil {
+			return fmt.Errorf("cannot create account: %v", err)
+		}
+		resources.accountID = *account.ID
+		utils.ConsoleOutput(fmt.Sprintf("Account successfully created, resource id: %v", resources.accountID))
+
+		utils.ConsoleOutput(fmt.Sprintf("Creating Capacity Pool %v...", scenario.PoolName))
+		capacityPool, err := sdkutils.CreateAnfCapacityPool(cntx, scenario.Location, scenario.ResourceGroup, scenario.AccountName, scenario.PoolName, scenario.ServiceLevel, scenario.PoolSizeBytes, tags)
+		if err != nil {
+			return fmt.Errorf("cannot create capacity pool: %v", err)
+		}
+		resources.capacityPoolID = *capacityPool.ID
+		utils.ConsoleOutput(fmt.Sprintf("Capacity Pool successfully created, resource id: %v", resources.capacityPoolID))
+	}
+
+	utils.ConsoleOutput(fmt.Sprintf("Creating Snapshot Policy %v...", scenario.SnapshotPolicy.Name))
+	snapshotPolicyProperties, err := scenario.SnapshotPolicy.Properties()
+	if err != nil {
+		return fmt.Errorf("invalid snapshot policy: %v", err)
+	}
+
+	// CreateAnfSnapshotPolicy is a PUT: when attached to an existing account
+	// this upserts a policy with this name instead of minting a duplicate,
+	// so attach mode never leaves behind a policy with no matching cleanup.
+	snapshotPolicy, err := sdkutils.CreateAnfSnapshotPolicy(cntx, policyResourceGroup, policyAccountName, scenario.SnapshotPolicy.Name, netapp.SnapshotPolicy{
+		Location:                 to.StringPtr(scenario.Location),
+		Name:                     to.StringPtr(scenario.SnapshotPolicy.Name),
+		SnapshotPolicyProperties: snapshotPolicyProperties,
+		Tags:                     tags,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot create snapshot policy: %v", err)
+	}
+	if attachToVolumeID == "" {
+		resources.snapshotPolicyID = *snapshotPolicy.ID
+	}
+	utils.ConsoleOutput(fmt.Sprintf("Snapshot Policy successfully created, resource id: %v", *snapshotPolicy.ID))
+
+	if attachToVolumeID != "" {
+		utils.ConsoleOutput(fmt.Sprintf("Attaching Snapshot Policy %v to existing volume %v...", scenario.SnapshotPolicy.Name, attachToVolumeID))
+		if err := sdkutils.AttachSnapshotPolicyToVolume(cntx, policyResourceGroup, policyAccountName, attachPool, attachVolume, *snapshotPolicy.ID); err != nil {
+			return fmt.Errorf("cannot attach snapshot policy to volume: %v", err)
+		}
+
+		utils.ConsoleOutput("Snapshot Policy successfully attached to existing volume")
+		return nil
+	}
+
+	if err := demonstrateSnapshotPolicyPatch(cntx, scenario, resources.snapshotPolicyID); err != nil {
+		return err
+	}
+
+	utils.ConsoleOutput(fmt.Sprintf("Creating NFSv3 Volume %v with Snapshot Policy %v attached...", scenario.Volume.Name, scenario.SnapshotPolicy.Name))
+	subnetID := fmt.Sprintf("/subscriptions/%v/resourceGroups/%v/providers/Microsoft.Network/virtualNetworks/%v/subnets/%v",
+		subscriptionID,
+		scenario.Volume.VnetResourceGroup,
+		scenario.Volume.VnetName,
+		scenario.Volume.SubnetName,
+	)
+	dataProtectionObject := netapp.VolumePropertiesDataProtection{
+		Snapshot: &netapp.VolumeSnapshotProperties{
+			SnapshotPolicyID: to.StringPtr(resources.snapshotPolicyID),
+		},
+	}
+
+	volume, err := sdkutils.CreateAnfVolume(
+		cntx,
+		scenario.Location,
+		scenario.ResourceGroup,
+		scenario.AccountName,
+		scenario.PoolName,
+		scenario.Volume.Name,
+		scenario.Volume.ServiceLevel,
+		subnetID,
+		"",
+		scenario.Volume.ProtocolTypes,
+		scenario.Volume.SizeBytes,
+		false,
+		true,
+		tags,
+		dataProtectionObject,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot create volume: %v", err)
+	}
+	resources.volumeID = *volume.ID
+	utils.ConsoleOutput(fmt.Sprintf("Volume successfully created, resource id: %v", resources.volumeID))
+
+	utils.ConsoleOutput("Waiting for volume to be ready...")
+	if err := sdkutils.WaitForANFResource(cntx, resources.volumeID, 60, 50, false); err != nil {
+		return fmt.Errorf("error waiting for volume: %v", err)
+	}
+
+	return nil
+}
+
+// demonstrateSnapshotPolicyPatch disables the snapshot policy as a whole -
+// the ANF API has no per-schedule enabled flag, so Enabled always applies to
+// every schedule at once - and sets the daily schedule to 22:00 keeping 10
+// snapshots (up from the default scenario's 5) via a PATCH, then polls until
+// the policy is ready again, reports its Enabled flag and daily
+// SnapshotsToKeep, and reports how many snapshot policies the account has.
+func demonstrateSnapshotPolicyPatch(cntx context.Context, scenario config.Scenario, snapshotPolicyID string) error {
+	utils.ConsoleOutput(fmt.Sprintf("Updating snapshot policy %v...", scenario.SnapshotPolicy.Name))
+
+	snapshotPolicyPatch := netapp.SnapshotPolicyPatch{
+		Location: to.StringPtr(scenario.Location),
+		SnapshotPolicyProperties: &netapp.SnapshotPolicyProperties{
+			DailySchedule: &netapp.DailySchedule{
+				Hour:            to.Int32Ptr(22),
+				Minute:          to.Int32Ptr(0),
+				SnapshotsToKeep: to.Int32Ptr(10),
+			},
+			Enabled: to.BoolPtr(false),
+		},
+	}
+
+	if _, err := sdkutils.UpdateAnfSnapshotPolicy(cntx, scenario.ResourceGroup, scenario.AccountName, scenario.SnapshotPolicy.Name, snapshotPolicyPatch); err != nil {
+		return fmt.Errorf("cannot update snapshot policy: %v", err)
+	}
+
+	utils.ConsoleOutput("Waiting for snapshot policy update to complete...")
+	if err := sdkutils.WaitForANFResource(cntx, snapshotPolicyID, 60, 50, false); err != nil {
+		return fmt.Errorf("error waiting for snapshot policy: %v", err)
+	}
+
+	updated, err := sdkutils.GetAnfSnapshotPolicy(cntx, scenario.ResourceGroup, scenario.AccountName, scenario.SnapshotPolicy.Name)
+	if err != nil {
+		return fmt.Errorf("cannot retrieve snapshot policy: %v", err)
+	}
+	utils.ConsoleOutput(fmt.Sprintf("Snapshot Policy successfully updated, policy enabled: %v, daily snapshots to keep: %v",
+		*updated.Enabled,
+		*updated.DailySchedule.SnapshotsToKeep,
+	))
+
+	policies, err := sdkutils.ListAnfSnapshotPolicies(cntx, scenario.ResourceGroup, scenario.AccountName)
+	if err != nil {
+		return fmt.Errorf("cannot list snapshot policies: %v", err)
+	}
+	utils.ConsoleOutput(fmt.Sprintf("Account %v currently has %v snapshot policy(ies)", scenario.AccountName, len(policies)))
+
+	return nil
+}
+
+// cleanupScenario tears down, in reverse order, the volume, capacity pool,
+// and account this scenario run actually created. The snapshot policy is
+// only used to prune the volume's snapshots; the policy itself is not
+// deleted. A failure midway is logged but does not stop the rest of the
+// teardown from being attempted.
+func cleanupScenario(cntx context.Context, scenario config.Scenario, resources scenarioResources) {
+	utils.ConsoleOutput(fmt.Sprintf("\tPerforming clean up for scenario %v", scenario.Name))
+
+	if resources.volumeID != "" {
+		utils.ConsoleOutput(fmt.Sprintf("\tRemoving data protection object from %v volume...", scenario.Volume.Name))
+		err := sdkutils.DeleteAnfVolumeReplication(cntx, scenario.ResourceGroup, scenario.AccountName, scenario.PoolName, scenario.Volume.Name)
+		if err != nil && !strings.Contains(err.Error(), "VolumeReplicationMissing") {
+			utils.ConsoleOutput(fmt.Sprintf("an error ocurred while deleting data replication: %v", err))
+		} else {
+			sdkutils.WaitForNoANFResource(cntx, resources.volumeID, 60, 50, true)
+			utils.ConsoleOutput("\tData replication successfully deleted")
+		}
+
+		utils.ConsoleOutput(fmt.Sprintf("\tPruning stale snapshots from %v volume...", scenario.Volume.Name))
+		err = sdkutils.PruneSnapshotsByPolicy(cntx, scenario.ResourceGroup, scenario.AccountName, scenario.PoolName, scenario.Volume.Name, resources.snapshotPolicyID, sdkutils.SnapshotRetention{DryRun: scenario.Cleanup.IsDryRunPrune()})
+		if err != nil {
+			utils.ConsoleOutput(fmt.Sprintf("an error ocurred while pruning snapshots: %v", err))
+		} else {
+			utils.ConsoleOutput("\tStale snapshots pruned")
+		}
+
+		utils.ConsoleOutput(fmt.Sprintf("\tRemoving %v volume...", resources.volumeID))
+		if err := sdkutils.DeleteAnfVolume(cntx, scenario.ResourceGroup, scenario.AccountName, scenario.PoolName, scenario.Volume.Name); err != nil {
+			utils.ConsoleOutput(fmt.Sprintf("an error ocurred while deleting volume: %v", err))
+		} else {
+			sdkutils.WaitForNoANFResource(cntx, resources.volumeID, 60, 50, false)
+			utils.ConsoleOutput("\tVolume successfully deleted")
+		}
+	}
+
+	if resources.capacityPoolID != "" {
+		utils.ConsoleOutput(fmt.Sprintf("\tCleaning up capacity pool %v...", resources.capacityPoolID))
+		if err := sdkutils.DeleteAnfCapacityPool(cntx, scenario.ResourceGroup, scenario.AccountName, scenario.PoolName); err != nil {
+			utils.ConsoleOutput(fmt.Sprintf("an error ocurred while deleting capacity pool: %v", err))
+		} else {
+			sdkutils.WaitForNoANFResource(cntx, resources.capacityPoolID, 60, 50, false)
+			utils.ConsoleOutput("\tCapacity pool successfully deleted")
+		}
+	}
+
+	if resources.accountID != "" {
+		utils.ConsoleOutput(fmt.Sprintf("\tCleaning up account %v...", resources.accountID))
+		if err := sdkutils.DeleteAnfAccount(cntx, scenario.ResourceGroup, scenario.AccountName); err != nil {
+			utils.ConsoleOutput(fmt.Sprintf("an error ocurred while deleting account: %v", err))
+		} else {
+			utils.ConsoleOutput("\tAccount successfully deleted")
+		}
+	}
+
+	utils.ConsoleOutput(fmt.Sprintf("\tCleanup completed for scenario %v!", scenario.Name))
+}
+
+func toStringPtrTags(tags map[string]string) map[string]*string {
+	if tags == nil {
+		return nil
+	}
+
+	result := make(map[string]*string, len(tags))
+	for k, v := range tags {
+		v := v
+		result[k] = &v
+	}
+	return result
+}
+
+// defaultScenario reproduces the sample's original hard-coded values, used
+// whenever no -config/ANF_SAMPLE_CONFIG scenario file is supplied. It still
+// honors ANF_POLICY_TEMPLATE so the original "pick a template via env var"
+// workflow keeps working without a scenario file; when unset, Template is
+// "" and Properties() falls back to the ad-hoc Hourly/Daily/Weekly/Monthly
+// schedule below, same as before ANF_POLICY_TEMPLATE existed.
+func defaultScenario() config.Scenario {
+	accountName := haikunator.New(time.Now().UTC().UnixNano()).Haikunate()
+	poolName := "Pool01"
+
+	return config.Scenario{
+		Name:          "default",
+		Location:      "westus",
+		ResourceGroup: "anf-rg",
+		AccountName:   accountName,
+		PoolName:      poolName,
+		PoolSizeBytes: 4398046511104, // 4TiB (minimum capacity pool size)
+		ServiceLevel:  "Standard",
+		Tags: map[string]string{
+			"Author":  "ANF Go Snapshot Policy SDK Sample",
+			"Service": "Azure Netapp Files",
+		},
+		SnapshotPolicy: config.SnapshotPolicySpec{
+			Name:     "snapshotpolicy01",
+			Template: os.Getenv("ANF_POLICY_TEMPLATE"),
+			Enabled:  to.BoolPtr(true),
+			Hourly:   &config.HourlySchedule{Minute: 50, SnapshotsToKeep: 5},
+			Daily:    &config.DailySchedule{Hour: 22, Minute: 0, SnapshotsToKeep: 5},
+			Weekly:   &config.WeeklySchedule{Day: "Friday", Hour: 23, Minute: 0, SnapshotsToKeep: 5},
+			Monthly:  &config.MonthlySchedule{DaysOfMonth: "1,15,25", Hour: 8, Minute: 0, SnapshotsToKeep: 5},
+		},
+		Volume: config.VolumeSpec{
+			Name:              fmt.Sprintf("NFSv3-Vol-%v-%v", accountName, poolName),
+			SizeBytes:         107374182400, // 100GiB (minimum volume size)
+			ServiceLevel:      "Standard",
+			ProtocolTypes:     []string{"NFSv3"},
+			VnetResourceGroup: "anf-rg",
+			VnetName:          "westus-vnet",
+			SubnetName:        "anf-sn",
+		},
+		Cleanup: config.CleanupPolicy{Enabled: to.BoolPtr(false)},
+	}
+}